@@ -1,20 +1,61 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-	"bytes"
-	"encoding/base64"
+	"sync"
+	"syscall"
+
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// copyBufferSize is the chunk size used when streaming a cross-volume copy.
+const copyBufferSize = 4 * 1024 * 1024 // 4MB
+
+// MoveOptions controls how MoveVideos behaves.
+type MoveOptions struct {
+	// DryRun, when true, computes the plan (output directory, per-file
+	// actions) and emits progress events but performs no filesystem changes.
+	DryRun bool
+	// ContinueOnError, when true, keeps processing the remaining files after
+	// a failure instead of aborting the whole batch. Failures are collected
+	// into MoveSummary.Failed.
+	ContinueOnError bool
+}
+
+// MoveFailure records why a single file could not be moved.
+type MoveFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// MoveSummary is returned by MoveVideos and describes what actually happened,
+// so partial batches (when ContinueOnError is set) don't leave the frontend
+// guessing which files made it.
+type MoveSummary struct {
+	OutputDir string        `json:"outputDir"`
+	Moved     []string      `json:"moved"`
+	Failed    []MoveFailure `json:"failed"`
+	DryRun    bool          `json:"dryRun"`
+}
+
 // App struct (Unchanged)
 type App struct {
 	ctx context.Context
+
+	thumbCacheOnce sync.Once
+	thumbCache     *ThumbnailCache
 }
 
 // NewApp (Unchanged)
@@ -26,6 +67,13 @@ func NewApp() *App {
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	runtime.LogInfo(a.ctx, "Go Backend Started.")
+	installSignalHandler()
+}
+
+// shutdown is called by Wails as the app quits. It cleans up any partial
+// output left behind by an in-flight NormalizeVideos job.
+func (a *App) shutdown(ctx context.Context) {
+	cleanupWipJobs()
 }
 
 // --- Bound Go Functions ---
@@ -51,7 +99,14 @@ func (a *App) SelectDirectory() (string, error) {
 
 // MoveVideos takes a list of absolute video file paths and moves them
 // into a new directory named after the first video.
-func (a *App) MoveVideos(absoluteFilePaths []string) error {
+//
+// Moves are attempted with os.Rename first. If that fails because the
+// destination is on a different volume (EXDEV), it falls back to a
+// streamed copy with SHA-256 verification before removing the source, so
+// cross-drive moves succeed instead of aborting. opts.ContinueOnError keeps
+// the batch going after a failure instead of stopping on the first one;
+// opts.DryRun reports the plan without touching the filesystem.
+func (a *App) MoveVideos(absoluteFilePaths []string, opts MoveOptions) (*MoveSummary, error) {
 	runtime.LogInfo(a.ctx, fmt.Sprintf("MoveVideos called with %d files.", len(absoluteFilePaths)))
 	runtime.EventsEmit(a.ctx, "move-status", fmt.Sprintf("Received request to move %d files.", len(absoluteFilePaths)))
 
@@ -59,7 +114,7 @@ func (a *App) MoveVideos(absoluteFilePaths []string) error {
 		err := fmt.Errorf("no video files provided to move")
 		runtime.LogError(a.ctx, err.Error())
 		runtime.EventsEmit(a.ctx, "move-error", err.Error())
-		return err
+		return nil, err
 	}
 
 	// --- Determine Output Directory Path ---
@@ -86,19 +141,34 @@ func (a *App) MoveVideos(absoluteFilePaths []string) error {
 	runtime.LogInfo(a.ctx, fmt.Sprintf("Target output directory for moved files: %s", outputDir))
 	runtime.EventsEmit(a.ctx, "move-status", fmt.Sprintf("Target directory: %s", outputDir))
 
+	summary := &MoveSummary{OutputDir: outputDir, DryRun: opts.DryRun}
+
 	// --- Create Output Directory ---
-	err := os.MkdirAll(outputDir, os.ModePerm) // 0755 permission
-	if err != nil {
-		errMsg := fmt.Sprintf("failed to create output directory '%s': %v", outputDir, err)
-		runtime.LogError(a.ctx, errMsg)
-		runtime.EventsEmit(a.ctx, "move-error", errMsg)
-		return fmt.Errorf(errMsg)
+	if !opts.DryRun {
+		err := os.MkdirAll(outputDir, os.ModePerm) // 0755 permission
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to create output directory '%s': %v", outputDir, err)
+			runtime.LogError(a.ctx, errMsg)
+			runtime.EventsEmit(a.ctx, "move-error", errMsg)
+			return nil, fmt.Errorf(errMsg)
+		}
+		runtime.LogInfo(a.ctx, "Output directory created or already exists.")
+		runtime.EventsEmit(a.ctx, "move-status", "Output directory created.")
+	}
+
+	// --- Compute total size up front so progress events can report an
+	// overall percentage, not just a per-file one. ---
+	var totalBytes int64
+	fileSizes := make(map[string]int64, len(absoluteFilePaths))
+	for _, p := range absoluteFilePaths {
+		if info, statErr := os.Stat(p); statErr == nil {
+			fileSizes[p] = info.Size()
+			totalBytes += info.Size()
+		}
 	}
-	runtime.LogInfo(a.ctx, "Output directory created or already exists.")
-	runtime.EventsEmit(a.ctx, "move-status", "Output directory created.")
 
 	// --- Move Files ---
-	movedCount := 0
+	var movedBytes int64
 	runtime.EventsEmit(a.ctx, "move-status", "Starting file move process...")
 	for _, originalPath := range absoluteFilePaths {
 		fileName := filepath.Base(originalPath)
@@ -107,36 +177,167 @@ func (a *App) MoveVideos(absoluteFilePaths []string) error {
 		runtime.LogInfo(a.ctx, fmt.Sprintf("Attempting to move '%s' to '%s'", originalPath, newPath))
 		runtime.EventsEmit(a.ctx, "move-status", fmt.Sprintf("Moving %s...", fileName))
 
-		// Use os.Rename to move the file.
-		// Note: This usually only works reliably on the same filesystem/volume.
-		// For cross-volume moves, a copy + delete approach is needed.
-		err := os.Rename(originalPath, newPath)
+		if opts.DryRun {
+			movedBytes += fileSizes[originalPath]
+			a.emitMoveProgress(fileName, fileSizes[originalPath], fileSizes[originalPath], movedBytes, totalBytes)
+			summary.Moved = append(summary.Moved, newPath)
+			continue
+		}
+
+		err := a.moveOneVideo(originalPath, newPath, fileSizes[originalPath], &movedBytes, totalBytes)
 		if err != nil {
-			// Attempt to provide more context on error
 			_, statErr := os.Stat(originalPath)
+			var errMsg string
 			if os.IsNotExist(statErr) {
-				errMsg := fmt.Sprintf("Failed to move file '%s': Source file not found.", fileName)
-				runtime.LogError(a.ctx, errMsg)
-				runtime.EventsEmit(a.ctx, "move-error", errMsg)
-				return fmt.Errorf(errMsg) // Stop on critical error
+				errMsg = fmt.Sprintf("Failed to move file '%s': Source file not found.", fileName)
+			} else {
+				errMsg = fmt.Sprintf("Failed to move file '%s' to '%s': %v", fileName, newPath, err)
 			}
-
-			// Generic rename error
-			errMsg := fmt.Sprintf("Failed to move file '%s' to '%s': %v", fileName, newPath, err)
 			runtime.LogError(a.ctx, errMsg)
-			runtime.EventsEmit(a.ctx, "move-error", errMsg+". Might be cross-drive issue or permissions.")
-			// Decide whether to stop or continue. Let's stop on first error for simplicity.
-			return fmt.Errorf(errMsg)
+			runtime.EventsEmit(a.ctx, "move-error", errMsg)
+
+			if !opts.ContinueOnError {
+				return summary, fmt.Errorf(errMsg)
+			}
+			summary.Failed = append(summary.Failed, MoveFailure{Path: originalPath, Error: errMsg})
+			continue
 		}
+
 		runtime.LogInfo(a.ctx, fmt.Sprintf("Successfully moved %s", fileName))
-		movedCount++
+		summary.Moved = append(summary.Moved, newPath)
 	}
 
-	successMsg := fmt.Sprintf("Successfully moved %d files to %s", movedCount, outputDir)
+	successMsg := fmt.Sprintf("Moved %d of %d files to %s", len(summary.Moved), len(absoluteFilePaths), outputDir)
+	if len(summary.Failed) > 0 {
+		successMsg += fmt.Sprintf(" (%d failed)", len(summary.Failed))
+	}
 	runtime.LogInfo(a.ctx, successMsg)
-	runtime.EventsEmit(a.ctx, "move-complete", successMsg) // Emit final success message
+	runtime.EventsEmit(a.ctx, "move-complete", successMsg)
+
+	return summary, nil
+}
+
+// moveOneVideo moves a single file from originalPath to newPath, preferring
+// a plain os.Rename and falling back to a streamed copy+verify+delete when
+// the destination is on a different volume.
+func (a *App) moveOneVideo(originalPath, newPath string, size int64, movedBytes *int64, totalBytes int64) error {
+	fileName := filepath.Base(originalPath)
+
+	err := os.Rename(originalPath, newPath)
+	if err == nil {
+		*movedBytes += size
+		a.emitMoveProgress(fileName, size, size, *movedBytes, totalBytes)
+		return nil
+	}
+
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	runtime.LogInfo(a.ctx, fmt.Sprintf("Rename failed across volumes for '%s', falling back to copy+verify.", fileName))
+	return a.copyVerifyDelete(originalPath, newPath, size, movedBytes, totalBytes)
+}
+
+// copyVerifyDelete streams originalPath to newPath, verifies the copy with a
+// SHA-256 checksum of both files, and only then removes the source. This is
+// the safe path for cross-device moves where os.Rename cannot be used.
+func (a *App) copyVerifyDelete(originalPath, newPath string, size int64, movedBytes *int64, totalBytes int64) (err error) {
+	fileName := filepath.Base(originalPath)
+
+	src, err := os.Open(originalPath)
+	if err != nil {
+		return fmt.Errorf("opening source: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(newPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("creating destination: %w", err)
+	}
+
+	// Any error from here on leaves a partial or unverified file at
+	// newPath; remove it so a failed move never leaves corrupt output
+	// behind for ContinueOnError batches to silently ship.
+	defer func() {
+		if err != nil {
+			os.Remove(newPath)
+		}
+	}()
+
+	srcHash := sha256.New()
+	dstHash := sha256.New()
+	reader := bufio.NewReaderSize(src, copyBufferSize)
+	writer := io.MultiWriter(dst, dstHash)
+	buf := make([]byte, copyBufferSize)
+
+	var copied int64
+	for {
+		n, readErr := reader.Read(buf)
+		if n > 0 {
+			chunk := buf[:n]
+			srcHash.Write(chunk)
+			if _, writeErr := writer.Write(chunk); writeErr != nil {
+				dst.Close()
+				return fmt.Errorf("writing destination: %w", writeErr)
+			}
+			copied += int64(n)
+			*movedBytes += int64(n)
+			a.emitMoveProgress(fileName, copied, size, *movedBytes, totalBytes)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			dst.Close()
+			return fmt.Errorf("reading source: %w", readErr)
+		}
+	}
+
+	if syncErr := dst.Sync(); syncErr != nil {
+		dst.Close()
+		return fmt.Errorf("fsync destination: %w", syncErr)
+	}
+	if closeErr := dst.Close(); closeErr != nil {
+		return fmt.Errorf("closing destination: %w", closeErr)
+	}
+
+	if verifyErr := verifyChecksumsMatch(srcHash.Sum(nil), dstHash.Sum(nil), newPath, fileName); verifyErr != nil {
+		return verifyErr
+	}
+
+	if removeErr := os.Remove(originalPath); removeErr != nil {
+		return fmt.Errorf("copy verified but failed to remove source: %w", removeErr)
+	}
+
+	return nil
+}
 
-	return nil // Success
+// verifyChecksumsMatch compares the source and destination checksums from a
+// copyVerifyDelete run. On mismatch it removes the (presumably corrupt)
+// destination file and returns an error instead of letting a bad copy stand.
+func verifyChecksumsMatch(srcSum, dstSum []byte, destPath, fileName string) error {
+	if hex.EncodeToString(srcSum) != hex.EncodeToString(dstSum) {
+		os.Remove(destPath)
+		return fmt.Errorf("checksum mismatch after copying %s, destination removed", fileName)
+	}
+	return nil
+}
+
+// emitMoveProgress reports per-file and overall move progress so the
+// frontend can render a progress bar.
+func (a *App) emitMoveProgress(fileName string, fileDone, fileTotal, overallDone, overallTotal int64) {
+	percent := 0.0
+	if overallTotal > 0 {
+		percent = float64(overallDone) / float64(overallTotal) * 100
+	}
+	runtime.EventsEmit(a.ctx, "move-progress", map[string]interface{}{
+		"file":           fileName,
+		"fileBytesDone":  fileDone,
+		"fileBytesTotal": fileTotal,
+		"overallBytes":   overallDone,
+		"overallTotal":   overallTotal,
+		"percent":        percent,
+	})
 }
 
 // GenerateThumbnail generates a thumbnail for a given video file.
@@ -160,6 +361,22 @@ func (a *App) MoveVideos(absoluteFilePaths []string) error {
 func (a *App) GenerateThumbnail(videoPath string) (string, error) {
 	runtime.LogInfo(a.ctx, fmt.Sprintf("Generating thumbnail Data URL for: %s", videoPath))
 
+	imageBytes, err := extractThumbnailFrame(videoPath)
+	if err != nil {
+		runtime.LogError(a.ctx, err.Error())
+		return "", err
+	}
+
+	dataURL := jpegDataURL(imageBytes)
+	runtime.LogInfo(a.ctx, fmt.Sprintf("Successfully generated thumbnail Data URL for: %s (Data URL length: %d)", videoPath, len(dataURL)))
+
+	return dataURL, nil
+}
+
+// extractThumbnailFrame shells out to ffmpeg to grab a single frame from
+// videoPath and returns the raw JPEG bytes. Shared by GenerateThumbnail and
+// the concurrent thumbnail pool so both go through the same ffmpeg recipe.
+func extractThumbnailFrame(videoPath string) ([]byte, error) {
 	// ffmpeg command arguments:
 	// -i videoPath : Input video file
 	// -ss 00:00:01 : Seek to the 1-second mark (adjust if needed)
@@ -178,9 +395,7 @@ func (a *App) GenerateThumbnail(videoPath string) (string, error) {
 	err := cmd.Run()
 	if err != nil {
 		// If ffmpeg fails, log the error and stderr content for diagnostics
-		errMsg := fmt.Sprintf("ffmpeg execution failed for %s: %v. Stderr: %s", videoPath, err, errb.String())
-		runtime.LogError(a.ctx, errMsg)
-		return "", fmt.Errorf(errMsg) // Return an empty string and the error
+		return nil, fmt.Errorf("ffmpeg execution failed for %s: %v. Stderr: %s", videoPath, err, errb.String())
 	}
 
 	// Get the raw image bytes from the stdout buffer
@@ -188,27 +403,13 @@ func (a *App) GenerateThumbnail(videoPath string) (string, error) {
 
 	// Check if ffmpeg actually produced any output
 	if len(imageBytes) == 0 {
-		errMsg := fmt.Sprintf("ffmpeg produced no thumbnail data for %s. Stderr: %s", videoPath, errb.String())
-		// Log as warning or error based on whether stderr had content
-		if errb.Len() > 0 {
-			runtime.LogWarning(a.ctx, errMsg) // May be warnings in stderr even on success
-		} else {
-			runtime.LogError(a.ctx, errMsg) // No output and no stderr likely means a problem
-		}
-		// Return error as we expect image data
-		return "", fmt.Errorf("ffmpeg produced no thumbnail data for video: %s", videoPath)
+		return nil, fmt.Errorf("ffmpeg produced no thumbnail data for video: %s (stderr: %s)", videoPath, errb.String())
 	}
 
-	// Encode the raw image bytes to a Base64 string
-	encodedString := base64.StdEncoding.EncodeToString(imageBytes)
-
-	// Format the Base64 string as a JPEG Data URL
-	// The MIME type "image/jpeg" matches the "-c:v mjpeg" ffmpeg argument.
-	// If you change the codec (e.g., to png), update the MIME type accordingly.
-	dataURL := fmt.Sprintf("data:image/jpeg;base64,%s", encodedString)
-
-	runtime.LogInfo(a.ctx, fmt.Sprintf("Successfully generated thumbnail Data URL for: %s (Data URL length: %d)", videoPath, len(dataURL)))
+	return imageBytes, nil
+}
 
-	// Return the Data URL string and nil error
-	return dataURL, nil
+// jpegDataURL formats raw JPEG bytes as a base64 data URL.
+func jpegDataURL(imageBytes []byte) string {
+	return fmt.Sprintf("data:image/jpeg;base64,%s", base64.StdEncoding.EncodeToString(imageBytes))
 }
\ No newline at end of file
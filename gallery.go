@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+//go:embed assets/gallery.html.tmpl
+var galleryTemplateSrc string
+
+var galleryTemplate = template.Must(template.New("gallery").Parse(galleryTemplateSrc))
+
+// GalleryOptions configures ExportGallery's output.
+type GalleryOptions struct {
+	// Title is the gallery page heading and <title>. Defaults to the
+	// directory name when empty.
+	Title string `json:"title"`
+	// Columns is the number of grid columns. Defaults to 4 when 0.
+	Columns int `json:"columns"`
+	// InlineVideos, when true, renders playable <video> elements pointing
+	// at the original files instead of a thumbnail link.
+	InlineVideos bool `json:"inlineVideos"`
+}
+
+// galleryItem is the per-video data handed to the HTML template.
+type galleryItem struct {
+	Name             string
+	RelPath          string
+	ThumbnailDataURL template.URL
+}
+
+// galleryPage is the top-level data handed to the HTML template.
+type galleryPage struct {
+	Title        string
+	Columns      int
+	InlineVideos bool
+	Items        []galleryItem
+}
+
+// ExportGallery generates a self-contained index.html inside dir listing
+// every video directly in that folder, with thumbnails embedded as data
+// URLs so the page has no external asset dependencies. Intended to be
+// called right after MoveVideos has finished populating a grouped folder.
+func (a *App) ExportGallery(dir string, opts GalleryOptions) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("reading gallery directory '%s': %w", dir, err)
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = filepath.Base(dir)
+	}
+	columns := opts.Columns
+	if columns <= 0 {
+		columns = 4
+	}
+
+	page := galleryPage{Title: title, Columns: columns, InlineVideos: opts.InlineVideos}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isMediaFile(entry.Name(), nil) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		dataURL, err := a.generateGalleryThumbnail(path)
+		if err != nil {
+			runtime.LogWarning(a.ctx, fmt.Sprintf("gallery thumbnail failed for %s: %v", path, err))
+		}
+
+		page.Items = append(page.Items, galleryItem{
+			Name:             entry.Name(),
+			RelPath:          entry.Name(),
+			ThumbnailDataURL: template.URL(dataURL),
+		})
+	}
+
+	outputPath := filepath.Join(dir, "index.html")
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("creating gallery index '%s': %w", outputPath, err)
+	}
+	defer f.Close()
+
+	if err := galleryTemplate.Execute(f, page); err != nil {
+		return fmt.Errorf("rendering gallery template: %w", err)
+	}
+
+	runtime.LogInfo(a.ctx, fmt.Sprintf("Exported gallery for %s with %d items to %s", dir, len(page.Items), outputPath))
+	return nil
+}
+
+// generateGalleryThumbnail extracts a frame the same way GenerateThumbnail
+// does, composites a play-button overlay onto it, and returns it as a JPEG
+// data URL.
+func (a *App) generateGalleryThumbnail(videoPath string) (string, error) {
+	frame, err := extractThumbnailFrame(videoPath)
+	if err != nil {
+		return "", err
+	}
+
+	withOverlay, err := overlayPlayButton(frame)
+	if err != nil {
+		// A thumbnail without the overlay is still better than none.
+		runtime.LogWarning(a.ctx, fmt.Sprintf("play-button overlay failed for %s: %v", videoPath, err))
+		return jpegDataURL(frame), nil
+	}
+
+	return jpegDataURL(withOverlay), nil
+}
+
+// overlayPlayButton composites a simple translucent circle-and-triangle
+// play icon onto the center of a JPEG frame.
+func overlayPlayButton(jpegBytes []byte) ([]byte, error) {
+	src, err := jpeg.Decode(bytes.NewReader(jpegBytes))
+	if err != nil {
+		return nil, fmt.Errorf("decoding frame: %w", err)
+	}
+
+	bounds := src.Bounds()
+	canvas := image.NewRGBA(bounds)
+	draw.Draw(canvas, bounds, src, bounds.Min, draw.Src)
+
+	cx, cy := bounds.Dx()/2+bounds.Min.X, bounds.Dy()/2+bounds.Min.Y
+	radius := bounds.Dx()
+	if bounds.Dy() < radius {
+		radius = bounds.Dy()
+	}
+	radius /= 6
+	if radius < 8 {
+		radius = 8
+	}
+
+	circleColor := image.NewUniform(color.RGBA{0, 0, 0, 160})
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				draw.Draw(canvas, image.Rect(cx+x, cy+y, cx+x+1, cy+y+1), circleColor, image.Point{}, draw.Over)
+			}
+		}
+	}
+
+	// Right-pointing triangle inscribed in the circle.
+	x1, y1 := cx-radius/2, cy-radius/2
+	x2, y2 := cx-radius/2, cy+radius/2
+	x3, y3 := cx+radius/2, cy
+	triColor := image.NewUniform(color.NRGBA{255, 255, 255, 230})
+	for y := cy - radius; y <= cy+radius; y++ {
+		for x := cx - radius; x <= cx+radius; x++ {
+			if pointInTriangle(x, y, x1, y1, x2, y2, x3, y3) {
+				draw.Draw(canvas, image.Rect(x, y, x+1, y+1), triColor, image.Point{}, draw.Over)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, canvas, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("encoding frame: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// pointInTriangle reports whether (px,py) lies inside the triangle defined
+// by the three given vertices, via the standard same-side sign test.
+func pointInTriangle(px, py, x1, y1, x2, y2, x3, y3 int) bool {
+	sign := func(px, py, ax, ay, bx, by int) int {
+		return (px-bx)*(ay-by) - (ax-bx)*(py-by)
+	}
+	d1 := sign(px, py, x1, y1, x2, y2)
+	d2 := sign(px, py, x2, y2, x3, y3)
+	d3 := sign(px, py, x3, y3, x1, y1)
+
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGalleryTemplateEscapesFilenames(t *testing.T) {
+	page := galleryPage{
+		Title:   "Test Gallery",
+		Columns: 4,
+		Items: []galleryItem{
+			{Name: `"><script>alert(1)</script>.mp4`, RelPath: `"><script>alert(1)</script>.mp4`},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := galleryTemplate.Execute(&out, page); err != nil {
+		t.Fatalf("executing gallery template: %v", err)
+	}
+
+	if strings.Contains(out.String(), "<script>alert(1)</script>") {
+		t.Fatalf("expected filename to be escaped, got unescaped script tag in output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyChecksumsMatchRemovesDestinationOnMismatch(t *testing.T) {
+	destPath := filepath.Join(t.TempDir(), "dest.mp4")
+	if err := os.WriteFile(destPath, []byte("partial or corrupt data"), 0644); err != nil {
+		t.Fatalf("writing test destination file: %v", err)
+	}
+
+	err := verifyChecksumsMatch([]byte{0x01, 0x02}, []byte{0x03, 0x04}, destPath, "dest.mp4")
+	if err == nil {
+		t.Fatal("expected an error on checksum mismatch")
+	}
+	if _, statErr := os.Stat(destPath); !os.IsNotExist(statErr) {
+		t.Fatal("expected destination file to be removed after a checksum mismatch")
+	}
+}
+
+func TestVerifyChecksumsMatchKeepsDestinationOnMatch(t *testing.T) {
+	destPath := filepath.Join(t.TempDir(), "dest.mp4")
+	if err := os.WriteFile(destPath, []byte("good data"), 0644); err != nil {
+		t.Fatalf("writing test destination file: %v", err)
+	}
+
+	sum := []byte{0xaa, 0xbb, 0xcc}
+	if err := verifyChecksumsMatch(sum, sum, destPath, "dest.mp4"); err != nil {
+		t.Fatalf("expected no error on matching checksums, got: %v", err)
+	}
+	if _, statErr := os.Stat(destPath); statErr != nil {
+		t.Fatalf("expected destination file to remain after a checksum match: %v", statErr)
+	}
+}
+
+// TestCopyVerifyDeleteRemovesDestinationOnReadFailure forces copyVerifyDelete
+// down its "reading source" error branch (by pointing it at a directory,
+// which opens fine but fails on the first Read) and asserts the partially
+// created destination file is cleaned up rather than left behind as silent
+// garbage in the output folder.
+func TestCopyVerifyDeleteRemovesDestinationOnReadFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	unreadableSource := filepath.Join(dir, "not-a-video")
+	if err := os.Mkdir(unreadableSource, 0755); err != nil {
+		t.Fatalf("creating directory to stand in for an unreadable source: %v", err)
+	}
+	destPath := filepath.Join(dir, "dest.mp4")
+
+	a := &App{}
+	var movedBytes int64
+	err := a.copyVerifyDelete(unreadableSource, destPath, 0, &movedBytes, 0)
+	if err == nil {
+		t.Fatal("expected an error when the source can't be read")
+	}
+	if _, statErr := os.Stat(destPath); !os.IsNotExist(statErr) {
+		t.Fatal("expected the partially created destination file to be removed after a read failure")
+	}
+	if _, statErr := os.Stat(unreadableSource); statErr != nil {
+		t.Fatalf("source should be left untouched on failure: %v", statErr)
+	}
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// fingerprintSampleBytes is how much of the head and tail of a file is
+// hashed for duplicate detection. Hashing the whole file would be accurate
+// but far too slow for large video libraries.
+const fingerprintSampleBytes = 4 * 1024 * 1024 // 4MB
+
+// ScanOptions configures the grouping heuristics used by ScanDirectory.
+type ScanOptions struct {
+	// GroupWindowMinutes groups files whose ffprobe creation_time falls
+	// within this many minutes of each other. 0 disables the time rule.
+	GroupWindowMinutes int `json:"groupWindowMinutes"`
+	// RequireSameResolution narrows matches made by GroupWindowMinutes or
+	// FilenamePrefixDistance to pairs that also share the same
+	// width/height. It is a co-requisite filter, not a standalone match
+	// rule: on its own (with no time window or filename distance set) it
+	// matches nothing, because most of a real video library shares a
+	// handful of resolutions (1920x1080, 3840x2160, ...) and would
+	// otherwise transitively union the whole library into one group.
+	RequireSameResolution bool `json:"requireSameResolution"`
+	// FilenamePrefixDistance groups files whose base names (without
+	// extension) are within this Levenshtein distance of each other. 0
+	// disables the filename rule.
+	FilenamePrefixDistance int `json:"filenamePrefixDistance"`
+}
+
+// VideoMeta is the per-file metadata ScanDirectory extracts via ffprobe plus
+// a fast content fingerprint for duplicate detection.
+type VideoMeta struct {
+	Path         string    `json:"path"`
+	Size         int64     `json:"size"`
+	Duration     float64   `json:"duration"`
+	Codec        string    `json:"codec"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	CreationTime time.Time `json:"creationTime,omitempty"`
+	Fingerprint  string    `json:"fingerprint"`
+}
+
+// DuplicateSet is a group of files that share a content fingerprint.
+type DuplicateSet struct {
+	Fingerprint string   `json:"fingerprint"`
+	Paths       []string `json:"paths"`
+}
+
+// ProposedGroup is a suggested grouping the frontend can present to the user
+// to accept or reject before calling MoveVideos.
+type ProposedGroup struct {
+	Name   string   `json:"name"`
+	Paths  []string `json:"paths"`
+	Reason string   `json:"reason"`
+}
+
+// ScanResult is returned by ScanDirectory.
+type ScanResult struct {
+	Root       string          `json:"root"`
+	Files      []VideoMeta     `json:"files"`
+	Duplicates []DuplicateSet  `json:"duplicates"`
+	Groups     []ProposedGroup `json:"groups"`
+}
+
+// ScanDirectory walks root, extracts ffprobe metadata and a content
+// fingerprint for every media file, and proposes groupings based on
+// opts so the frontend can present auto-suggested groups before the user
+// commits to MoveVideos.
+func (a *App) ScanDirectory(root string, opts ScanOptions) ScanResult {
+	result := ScanResult{Root: root}
+
+	var paths []string
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return nil
+		}
+		if isMediaFile(path, nil) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ScanDirectory walk of %s failed: %v", root, err))
+		runtime.EventsEmit(a.ctx, "scan-progress", map[string]interface{}{"root": root, "error": err.Error()})
+		return result
+	}
+
+	runtime.EventsEmit(a.ctx, "scan-progress", map[string]interface{}{"root": root, "total": len(paths), "processed": 0})
+
+	for i, path := range paths {
+		meta, err := probeVideo(path)
+		if err != nil {
+			runtime.LogWarning(a.ctx, fmt.Sprintf("ffprobe failed for %s: %v", path, err))
+		}
+		meta.Path = path
+
+		if info, statErr := os.Stat(path); statErr == nil {
+			meta.Size = info.Size()
+			if fp, fpErr := fingerprintFile(path, info.Size()); fpErr == nil {
+				meta.Fingerprint = fp
+			} else {
+				runtime.LogWarning(a.ctx, fmt.Sprintf("fingerprinting failed for %s: %v", path, fpErr))
+			}
+		}
+
+		result.Files = append(result.Files, meta)
+		runtime.EventsEmit(a.ctx, "scan-progress", map[string]interface{}{
+			"root": root, "total": len(paths), "processed": i + 1, "path": path,
+		})
+	}
+
+	result.Duplicates = findDuplicates(result.Files)
+	result.Groups = proposeGroups(result.Files, opts)
+
+	return result
+}
+
+// probeVideo shells out to ffprobe to collect duration, codec, resolution
+// and creation time for path.
+func probeVideo(path string) (VideoMeta, error) {
+	cmd := exec.Command("ffprobe", "-v", "error",
+		"-show_entries", "stream=codec_name,width,height:format=duration:format_tags=creation_time",
+		"-of", "json", path)
+
+	var out, errb bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errb
+	if err := cmd.Run(); err != nil {
+		return VideoMeta{}, fmt.Errorf("ffprobe failed for %s: %v. Stderr: %s", path, err, errb.String())
+	}
+
+	var probe struct {
+		Streams []struct {
+			CodecName string `json:"codec_name"`
+			Width     int    `json:"width"`
+			Height    int    `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+			Tags     struct {
+				CreationTime string `json:"creation_time"`
+			} `json:"tags"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &probe); err != nil {
+		return VideoMeta{}, fmt.Errorf("parsing ffprobe output for %s: %w", path, err)
+	}
+
+	meta := VideoMeta{}
+	for _, s := range probe.Streams {
+		if s.Width > 0 && s.Height > 0 {
+			meta.Codec, meta.Width, meta.Height = s.CodecName, s.Width, s.Height
+			break
+		}
+	}
+	if d, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil {
+		meta.Duration = d
+	}
+	if probe.Format.Tags.CreationTime != "" {
+		if t, err := time.Parse(time.RFC3339, probe.Format.Tags.CreationTime); err == nil {
+			meta.CreationTime = t
+		}
+	}
+	return meta, nil
+}
+
+// fingerprintFile hashes the first and last fingerprintSampleBytes of path,
+// which is enough to catch exact duplicates without reading whole videos.
+func fingerprintFile(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	buf := make([]byte, fingerprintSampleBytes)
+
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	h.Write(buf[:n])
+
+	if size > int64(fingerprintSampleBytes) {
+		if _, err := f.Seek(-int64(fingerprintSampleBytes), io.SeekEnd); err != nil {
+			return "", err
+		}
+		n, err = io.ReadFull(f, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return "", err
+		}
+		h.Write(buf[:n])
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// findDuplicates groups files that share a content fingerprint.
+func findDuplicates(files []VideoMeta) []DuplicateSet {
+	byFingerprint := make(map[string][]string)
+	for _, f := range files {
+		if f.Fingerprint == "" {
+			continue
+		}
+		byFingerprint[f.Fingerprint] = append(byFingerprint[f.Fingerprint], f.Path)
+	}
+
+	var dups []DuplicateSet
+	for fp, paths := range byFingerprint {
+		if len(paths) > 1 {
+			dups = append(dups, DuplicateSet{Fingerprint: fp, Paths: paths})
+		}
+	}
+	return dups
+}
+
+// proposeGroups clusters files using a union-find over pairwise matches, so
+// A-matches-B and B-matches-C end up in the same group even if A and C
+// don't directly match.
+func proposeGroups(files []VideoMeta, opts ScanOptions) []ProposedGroup {
+	n := len(files)
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		if ri != rj {
+			parent[ri] = rj
+		}
+	}
+
+	clusterReasons := make(map[int]map[string]bool)
+	addReason := func(root int, reason string) {
+		if clusterReasons[root] == nil {
+			clusterReasons[root] = make(map[string]bool)
+		}
+		clusterReasons[root][reason] = true
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if reason, matches := filesMatch(files[i], files[j], opts); matches {
+				union(i, j)
+				addReason(find(i), reason)
+			}
+		}
+	}
+
+	clusters := make(map[int][]int)
+	for i := 0; i < n; i++ {
+		root := find(i)
+		clusters[root] = append(clusters[root], i)
+	}
+
+	var groups []ProposedGroup
+	for root, members := range clusters {
+		if len(members) < 2 {
+			continue
+		}
+		paths := make([]string, len(members))
+		for i, idx := range members {
+			paths[i] = files[idx].Path
+		}
+		ext := filepath.Ext(paths[0])
+		name := strings.TrimSuffix(filepath.Base(paths[0]), ext)
+
+		var reasonList []string
+		for reason := range clusterReasons[root] {
+			reasonList = append(reasonList, reason)
+		}
+		sort.Strings(reasonList)
+
+		groups = append(groups, ProposedGroup{
+			Name:   name,
+			Paths:  paths,
+			Reason: strings.Join(reasonList, ", "),
+		})
+	}
+	return groups
+}
+
+// filesMatch reports whether a and b should be proposed as part of the same
+// group under opts, and a short reason describing why.
+//
+// GroupWindowMinutes and FilenamePrefixDistance are opt-in, standalone match
+// rules: each is only evaluated when its threshold is configured, and a pair
+// matches as soon as either one fires. RequireSameResolution is different —
+// it's a co-requisite filter on top of those two, not a rule of its own, so
+// enabling it alone can never match anything; see its doc comment for why.
+func filesMatch(a, b VideoMeta, opts ScanOptions) (string, bool) {
+	var reasons []string
+
+	timeMatch := false
+	if opts.GroupWindowMinutes > 0 && !a.CreationTime.IsZero() && !b.CreationTime.IsZero() {
+		diff := a.CreationTime.Sub(b.CreationTime)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= time.Duration(opts.GroupWindowMinutes)*time.Minute {
+			timeMatch = true
+			reasons = append(reasons, "creation time within window")
+		}
+	}
+
+	filenameMatch := false
+	if opts.FilenamePrefixDistance > 0 {
+		nameA := strings.TrimSuffix(filepath.Base(a.Path), filepath.Ext(a.Path))
+		nameB := strings.TrimSuffix(filepath.Base(b.Path), filepath.Ext(b.Path))
+		if levenshtein(nameA, nameB) <= opts.FilenamePrefixDistance {
+			filenameMatch = true
+			reasons = append(reasons, "similar filenames")
+		}
+	}
+
+	if !timeMatch && !filenameMatch {
+		return "", false
+	}
+
+	if opts.RequireSameResolution {
+		if a.Width == 0 || a.Width != b.Width || a.Height != b.Height {
+			return "", false
+		}
+		reasons = append(reasons, "same resolution")
+	}
+
+	return strings.Join(reasons, ", "), true
+}
+
+// levenshtein computes the classic edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows, cols := len(ra)+1, len(rb)+1
+
+	prev := make([]int, cols)
+	curr := make([]int, cols)
+	for j := 0; j < cols; j++ {
+		prev[j] = j
+	}
+
+	for i := 1; i < rows; i++ {
+		curr[0] = i
+		for j := 1; j < cols; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = int(math.Min(
+				math.Min(float64(curr[j-1]+1), float64(prev[j]+1)),
+				float64(prev[j-1]+cost),
+			))
+		}
+		prev, curr = curr, prev
+	}
+	return prev[cols-1]
+}
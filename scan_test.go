@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"abc", "", 3},
+		{"IMG_0001", "IMG_0002", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, c := range cases {
+		if got := levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestFilesMatchRulesAreOptIn(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	sameRes := VideoMeta{Path: "/a/IMG_0001.mp4", Width: 1920, Height: 1080, CreationTime: now}
+	sameResOther := VideoMeta{Path: "/a/IMG_0002.mp4", Width: 1920, Height: 1080, CreationTime: now.Add(time.Hour)}
+
+	// Filename similarity must still be able to fire on its own with the
+	// time rule disabled.
+	if _, ok := filesMatch(sameRes, sameResOther, ScanOptions{FilenamePrefixDistance: 2}); !ok {
+		t.Error("expected filename-only match with GroupWindowMinutes disabled")
+	}
+
+	// With every rule disabled, nothing should match.
+	if _, ok := filesMatch(sameRes, sameResOther, ScanOptions{}); ok {
+		t.Error("expected no match when no rule is enabled")
+	}
+
+	// Time rule still works on its own when enabled and within the window.
+	if _, ok := filesMatch(sameRes, sameResOther, ScanOptions{GroupWindowMinutes: 90}); !ok {
+		t.Error("expected time-only match within the window")
+	}
+	if _, ok := filesMatch(sameRes, sameResOther, ScanOptions{GroupWindowMinutes: 10}); ok {
+		t.Error("expected no time-only match outside the window")
+	}
+}
+
+func TestFilesMatchResolutionIsNotStandalone(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	sameRes := VideoMeta{Path: "/a/IMG_0001.mp4", Width: 1920, Height: 1080, CreationTime: now}
+	// Far enough apart in time, and a dissimilar filename, that only
+	// resolution is shared.
+	sameResOther := VideoMeta{Path: "/a/totally_different_name.mp4", Width: 1920, Height: 1080, CreationTime: now.Add(24 * time.Hour)}
+
+	// RequireSameResolution alone (no time window or filename distance)
+	// must never match on its own, or a real library sharing one common
+	// resolution would get unioned into a single meaningless group.
+	if _, ok := filesMatch(sameRes, sameResOther, ScanOptions{RequireSameResolution: true}); ok {
+		t.Error("expected RequireSameResolution alone to never match")
+	}
+
+	// Combined with a firing time-window rule, resolution narrows the
+	// match rather than producing one by itself.
+	opts := ScanOptions{GroupWindowMinutes: 5, RequireSameResolution: true}
+	closeInTime := VideoMeta{Path: "/a/IMG_0002.mp4", Width: 1920, Height: 1080, CreationTime: now.Add(time.Minute)}
+	if _, ok := filesMatch(sameRes, closeInTime, opts); !ok {
+		t.Error("expected time+resolution match when both conditions hold")
+	}
+
+	diffRes := VideoMeta{Path: "/a/IMG_0003.mp4", Width: 640, Height: 480, CreationTime: now.Add(time.Minute)}
+	if _, ok := filesMatch(sameRes, diffRes, opts); ok {
+		t.Error("expected time+resolution to reject a pair with different resolutions")
+	}
+}
+
+func TestProposeGroupsResolutionAloneProducesNoGroups(t *testing.T) {
+	files := []VideoMeta{
+		{Path: "/a/clip1.mp4", Width: 1280, Height: 720},
+		{Path: "/a/clip2.mp4", Width: 1280, Height: 720},
+		{Path: "/a/unrelated.mp4", Width: 640, Height: 480},
+	}
+
+	groups := proposeGroups(files, ScanOptions{RequireSameResolution: true})
+	if len(groups) != 0 {
+		t.Fatalf("expected 0 groups from RequireSameResolution alone, got %d", len(groups))
+	}
+}
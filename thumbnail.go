@@ -0,0 +1,305 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// defaultThumbnailCacheBytes caps the on-disk thumbnail cache size before LRU
+// eviction kicks in.
+const defaultThumbnailCacheBytes int64 = 512 * 1024 * 1024 // 512MB
+
+// defaultMediaExtensions lists the file extensions PrewarmThumbnails treats
+// as videos worth thumbnailing.
+var defaultMediaExtensions = map[string]bool{
+	".mp4": true, ".mov": true, ".mkv": true, ".avi": true,
+	".webm": true, ".m4v": true, ".flv": true, ".wmv": true,
+}
+
+// ThumbResult is streamed from GenerateThumbnails as each job completes.
+type ThumbResult struct {
+	Path    string `json:"path"`
+	DataURL string `json:"dataURL"`
+	Cached  bool   `json:"cached"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ThumbnailCache is a persistent, on-disk JPEG cache keyed by the hash of a
+// video's path, mtime and size, so a changed file never serves a stale
+// thumbnail. Eviction is LRU, approximated by each cache file's mtime, which
+// is touched on every read.
+type ThumbnailCache struct {
+	dir      string
+	maxBytes int64
+
+	mu sync.Mutex
+}
+
+// newThumbnailCache creates (if needed) the on-disk cache directory under the
+// user's cache dir.
+func newThumbnailCache(maxBytes int64) (*ThumbnailCache, error) {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolving user cache dir: %w", err)
+	}
+	dir := filepath.Join(baseDir, "video-grouper", "thumbnails")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating thumbnail cache dir: %w", err)
+	}
+	return &ThumbnailCache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// keyFor derives the cache key for a file from its absolute path, mtime and
+// size, so edits to the source file invalidate the cached thumbnail.
+func (c *ThumbnailCache) keyFor(absPath string, info os.FileInfo) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%s:%d:%d", absPath, info.ModTime().Unix(), info.Size())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *ThumbnailCache) pathFor(key string) string {
+	return filepath.Join(c.dir, key+".jpg")
+}
+
+// get returns the cached JPEG bytes for key, touching its mtime so recently
+// used entries survive eviction longer.
+func (c *ThumbnailCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.pathFor(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// put writes data to the cache under key and evicts the least recently used
+// entries if the cache now exceeds its size cap.
+func (c *ThumbnailCache) put(key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.WriteFile(c.pathFor(key), data, 0644); err != nil {
+		return fmt.Errorf("writing thumbnail cache entry: %w", err)
+	}
+	return c.evictLocked()
+}
+
+// evictLocked removes the oldest (by mtime) cache entries until the total
+// cache size is back under maxBytes. Caller must hold c.mu.
+func (c *ThumbnailCache) evictLocked() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type fileInfo struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]fileInfo, 0, len(entries))
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, fileInfo{
+			path:    filepath.Join(c.dir, e.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err != nil {
+			continue
+		}
+		total -= f.size
+	}
+	return nil
+}
+
+// ensureThumbnailCache lazily initializes the App's thumbnail cache.
+func (a *App) ensureThumbnailCache() *ThumbnailCache {
+	a.thumbCacheOnce.Do(func() {
+		cache, err := newThumbnailCache(defaultThumbnailCacheBytes)
+		if err != nil {
+			wailsruntime.LogError(a.ctx, fmt.Sprintf("failed to initialize thumbnail cache: %v", err))
+			return
+		}
+		a.thumbCache = cache
+	})
+	return a.thumbCache
+}
+
+// thumbnailWorkerCount mirrors fastgallery's approach of capping concurrency
+// at min(8, NumCPU) so thumbnailing doesn't starve the rest of the machine.
+func thumbnailWorkerCount() int {
+	n := runtime.NumCPU()
+	if n > 8 {
+		return 8
+	}
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// generateThumbnailCached produces a data URL for path, serving from the
+// on-disk cache when the file's mtime+size match a previous run.
+func (a *App) generateThumbnailCached(cache *ThumbnailCache, path string) (string, bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	if cache != nil {
+		key := cache.keyFor(path, info)
+		if data, ok := cache.get(key); ok {
+			return jpegDataURL(data), true, nil
+		}
+
+		imageBytes, err := extractThumbnailFrame(path)
+		if err != nil {
+			return "", false, err
+		}
+		if putErr := cache.put(key, imageBytes); putErr != nil {
+			wailsruntime.LogWarning(a.ctx, fmt.Sprintf("failed to cache thumbnail for %s: %v", path, putErr))
+		}
+		return jpegDataURL(imageBytes), false, nil
+	}
+
+	imageBytes, err := extractThumbnailFrame(path)
+	if err != nil {
+		return "", false, err
+	}
+	return jpegDataURL(imageBytes), false, nil
+}
+
+// GenerateThumbnails fans a set of video paths out to a bounded worker pool
+// and blocks until every thumbnail is ready, returning the full set of
+// results. Each result is also emitted as a "thumb-ready" event as soon as
+// it completes, so the frontend can update incrementally instead of waiting
+// for the whole batch.
+//
+// Bound methods are marshalled to JSON across the Wails JS bridge, which
+// cannot carry a live channel, so this blocks rather than returning one; see
+// generateThumbnailsAsync for the channel-based version used internally.
+func (a *App) GenerateThumbnails(paths []string) []ThumbResult {
+	var results []ThumbResult
+	for result := range a.generateThumbnailsAsync(paths) {
+		results = append(results, result)
+	}
+	return results
+}
+
+// generateThumbnailsAsync is the unexported worker-pool implementation
+// behind GenerateThumbnails. It streams results over a channel so internal
+// callers like PrewarmThumbnails can consume them as they complete without
+// waiting on the full batch.
+func (a *App) generateThumbnailsAsync(paths []string) <-chan ThumbResult {
+	results := make(chan ThumbResult, len(paths))
+	cache := a.ensureThumbnailCache()
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	workers := thumbnailWorkerCount()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				dataURL, cached, err := a.generateThumbnailCached(cache, path)
+				result := ThumbResult{Path: path, DataURL: dataURL, Cached: cached}
+				if err != nil {
+					wailsruntime.LogError(a.ctx, fmt.Sprintf("thumbnail failed for %s: %v", path, err))
+					result.Error = err.Error()
+				}
+				wailsruntime.EventsEmit(a.ctx, "thumb-ready", result)
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		for _, p := range paths {
+			jobs <- p
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// isMediaFile reports whether path's extension is one of exts (or the
+// package default set when exts is nil).
+func isMediaFile(path string, exts map[string]bool) bool {
+	if exts == nil {
+		exts = defaultMediaExtensions
+	}
+	return exts[strings.ToLower(filepath.Ext(path))]
+}
+
+// PrewarmThumbnails walks dir in the background and fills the thumbnail
+// cache for every media file found, so the gallery is already warm by the
+// time the frontend asks for thumbnails.
+func (a *App) PrewarmThumbnails(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("prewarm target not found: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("prewarm target is not a directory: %s", dir)
+	}
+
+	go func() {
+		var paths []string
+		err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() {
+				return nil
+			}
+			if isMediaFile(path, nil) {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			wailsruntime.LogError(a.ctx, fmt.Sprintf("prewarm walk of %s failed: %v", dir, err))
+			return
+		}
+
+		wailsruntime.LogInfo(a.ctx, fmt.Sprintf("Prewarming %d thumbnails under %s", len(paths), dir))
+		for result := range a.generateThumbnailsAsync(paths) {
+			_ = result // results are surfaced via the thumb-ready event; nothing else to do here.
+		}
+		wailsruntime.LogInfo(a.ctx, fmt.Sprintf("Thumbnail prewarm of %s complete", dir))
+	}()
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThumbnailCacheKeyForChangesWithMtimeAndSize(t *testing.T) {
+	dir := t.TempDir()
+	cache := &ThumbnailCache{dir: dir, maxBytes: 1 << 20}
+
+	path := filepath.Join(dir, "video.mp4")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("writing test video: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat test video: %v", err)
+	}
+
+	if got, want := cache.keyFor(path, info), cache.keyFor(path, info); got != want {
+		t.Errorf("expected identical keys for the same path/info, got %q and %q", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("hello, but now considerably longer"), 0644); err != nil {
+		t.Fatalf("rewriting test video: %v", err)
+	}
+	changedInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat rewritten test video: %v", err)
+	}
+
+	if cache.keyFor(path, info) == cache.keyFor(path, changedInfo) {
+		t.Error("expected a different key after the file's size/mtime changed")
+	}
+}
+
+func TestThumbnailCachePutGetRoundTrip(t *testing.T) {
+	cache := &ThumbnailCache{dir: t.TempDir(), maxBytes: 1 << 20}
+
+	data := []byte("fake-jpeg-bytes")
+	if err := cache.put("somekey", data); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	got, ok := cache.get("somekey")
+	if !ok {
+		t.Fatal("expected a cache hit after put")
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %q, want %q", got, data)
+	}
+
+	if _, ok := cache.get("missing"); ok {
+		t.Error("expected a miss for a key that was never put")
+	}
+}
+
+// TestThumbnailCacheEvictLockedRemovesOldest exercises evictLocked directly
+// with crafted mtimes (rather than sleeping between writes) so the oldest
+// entry is deterministically the one reclaimed once the cache exceeds its
+// size cap.
+func TestThumbnailCacheEvictLockedRemovesOldest(t *testing.T) {
+	cache := &ThumbnailCache{dir: t.TempDir(), maxBytes: 12}
+
+	write := func(key string, data []byte, mtime time.Time) {
+		path := cache.pathFor(key)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("writing cache entry %s: %v", key, err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("setting mtime for %s: %v", key, err)
+		}
+	}
+
+	base := time.Now()
+	write("oldest", []byte("AAAAA"), base)
+	write("middle", []byte("BBBBB"), base.Add(time.Minute))
+	write("newest", []byte("CCCCC"), base.Add(2*time.Minute))
+	// 15 bytes total, over the 12-byte cap: eviction must kick in.
+
+	if err := cache.evictLocked(); err != nil {
+		t.Fatalf("evictLocked: %v", err)
+	}
+
+	if _, err := os.Stat(cache.pathFor("oldest")); !os.IsNotExist(err) {
+		t.Error("expected the oldest entry to be evicted")
+	}
+	if _, err := os.Stat(cache.pathFor("newest")); err != nil {
+		t.Errorf("expected the newest entry to survive eviction: %v", err)
+	}
+}
+
+func TestThumbnailCacheGetTouchesMtime(t *testing.T) {
+	cache := &ThumbnailCache{dir: t.TempDir(), maxBytes: 1 << 20}
+	if err := cache.put("k", []byte("data")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(cache.pathFor("k"), old, old); err != nil {
+		t.Fatalf("backdating mtime: %v", err)
+	}
+
+	if _, ok := cache.get("k"); !ok {
+		t.Fatal("expected a cache hit")
+	}
+
+	info, err := os.Stat(cache.pathFor("k"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if !info.ModTime().After(old) {
+		t.Error("expected get to touch the entry's mtime so it survives LRU eviction longer")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// NormalizeOptions controls a NormalizeVideos batch.
+type NormalizeOptions struct {
+	// MaxDimension caps the longest side of the transcoded video in pixels,
+	// mirroring fastgallery's videoMaxSize. 0 keeps the original size.
+	MaxDimension int `json:"maxDimension"`
+	// DryRun, when true, reports what would be transcoded without invoking
+	// ffmpeg.
+	DryRun bool `json:"dryRun"`
+}
+
+var jobCounter uint64
+
+// wipJobs tracks the partial output files of in-flight NormalizeVideos jobs,
+// keyed by job ID, along with the ffmpeg process writing each one, so both
+// the process and its output can be cleaned up if the app is interrupted
+// mid-transcode instead of leaving an orphaned ffmpeg writing to an unlinked
+// path.
+var (
+	wipJobsMu sync.Mutex
+	wipJobs   = make(map[string]map[string]*exec.Cmd)
+)
+
+var installSignalHandlerOnce sync.Once
+
+// installSignalHandler wires SIGINT/SIGTERM to clean up any in-flight
+// transcode output before the process exits, the same pattern fastgallery
+// uses in its signalHandler.
+func installSignalHandler() {
+	installSignalHandlerOnce.Do(func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-c
+			cleanupWipJobs()
+			os.Exit(1)
+		}()
+	})
+}
+
+// cleanupWipJobs kills the ffmpeg process behind every in-flight transcode
+// job and removes its partial output file. Safe to call from a signal
+// handler or app shutdown.
+func cleanupWipJobs() {
+	wipJobsMu.Lock()
+	defer wipJobsMu.Unlock()
+	for jobID, outputs := range wipJobs {
+		for path, cmd := range outputs {
+			if cmd != nil && cmd.Process != nil {
+				if err := cmd.Process.Kill(); err == nil {
+					fmt.Fprintf(os.Stderr, "killed in-flight ffmpeg for job %s: %s\n", jobID, path)
+				}
+			}
+			if err := os.Remove(path); err == nil {
+				fmt.Fprintf(os.Stderr, "cleaned up partial transcode output for job %s: %s\n", jobID, path)
+			}
+		}
+	}
+}
+
+func registerWipJob(jobID string) {
+	wipJobsMu.Lock()
+	wipJobs[jobID] = make(map[string]*exec.Cmd)
+	wipJobsMu.Unlock()
+}
+
+func unregisterWipJob(jobID string) {
+	wipJobsMu.Lock()
+	delete(wipJobs, jobID)
+	wipJobsMu.Unlock()
+}
+
+func addWipOutput(jobID, path string, cmd *exec.Cmd) {
+	wipJobsMu.Lock()
+	if outputs, ok := wipJobs[jobID]; ok {
+		outputs[path] = cmd
+	}
+	wipJobsMu.Unlock()
+}
+
+func removeWipOutput(jobID, path string) {
+	wipJobsMu.Lock()
+	if outputs, ok := wipJobs[jobID]; ok {
+		delete(outputs, path)
+	}
+	wipJobsMu.Unlock()
+}
+
+// newJobID returns a unique, monotonically distinguishable job identifier.
+func newJobID() string {
+	return fmt.Sprintf("job-%d-%d", time.Now().UnixNano(), atomic.AddUint64(&jobCounter, 1))
+}
+
+// normalizedOutputPath derives the transcode target for path: the same
+// directory and name, with a "_normalized.mp4" suffix.
+func normalizedOutputPath(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return base + "_normalized.mp4"
+}
+
+// NormalizeVideos transcodes the given videos to a consistent H.264 mp4
+// target before they're moved, so a later MoveVideos groups files that all
+// play and scale the same way. It runs in the background and returns a job
+// ID immediately; progress is reported via "transcode-progress" events.
+func (a *App) NormalizeVideos(paths []string, opts NormalizeOptions) (string, error) {
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no video files provided to normalize")
+	}
+
+	installSignalHandler()
+
+	jobID := newJobID()
+	registerWipJob(jobID)
+
+	go func() {
+		defer unregisterWipJob(jobID)
+
+		for _, path := range paths {
+			outputPath := normalizedOutputPath(path)
+
+			if opts.DryRun {
+				runtime.EventsEmit(a.ctx, "transcode-progress", map[string]interface{}{
+					"jobID": jobID, "path": path, "output": outputPath, "dryRun": true,
+				})
+				continue
+			}
+
+			err := a.transcodeOne(jobID, path, outputPath, opts)
+
+			if err != nil {
+				errMsg := fmt.Sprintf("transcode failed for %s: %v", path, err)
+				runtime.LogError(a.ctx, errMsg)
+				runtime.EventsEmit(a.ctx, "transcode-progress", map[string]interface{}{
+					"jobID": jobID, "path": path, "error": errMsg,
+				})
+				os.Remove(outputPath)
+				continue
+			}
+
+			runtime.EventsEmit(a.ctx, "transcode-progress", map[string]interface{}{
+				"jobID": jobID, "path": path, "output": outputPath, "done": true,
+			})
+		}
+	}()
+
+	return jobID, nil
+}
+
+// transcodeOne runs ffmpeg for a single file, parsing its `-progress pipe:2`
+// output and emitting a transcode-progress event for each progress block
+// ffmpeg reports (frame count, out_time and speed as of that block).
+func (a *App) transcodeOne(jobID, inputPath, outputPath string, opts NormalizeOptions) error {
+	args := []string{"-y", "-i", inputPath}
+	if opts.MaxDimension > 0 {
+		args = append(args, "-vf", fmt.Sprintf(
+			"scale='min(iw,%d)':'min(ih,%d)':force_original_aspect_ratio=decrease",
+			opts.MaxDimension, opts.MaxDimension))
+	}
+	args = append(args, "-c:v", "libx264", "-c:a", "aac", "-progress", "pipe:2", "-nostats", outputPath)
+
+	cmd := exec.Command("ffmpeg", args...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return fmt.Errorf("creating ffmpeg stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting ffmpeg: %w", err)
+	}
+	addWipOutput(jobID, outputPath, cmd)
+	defer removeWipOutput(jobID, outputPath)
+
+	progress := make(map[string]string)
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		progress[key] = value
+
+		// ffmpeg emits a "progress=continue|end" line at the end of each
+		// reporting block; that's our cue to flush a snapshot.
+		if key == "progress" {
+			runtime.EventsEmit(a.ctx, "transcode-progress", map[string]interface{}{
+				"jobID": jobID, "path": inputPath,
+				"frame": progress["frame"], "outTime": progress["out_time"], "speed": progress["speed"],
+			})
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("ffmpeg exited with error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNormalizedOutputPath(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"/a/b/video.mp4", "/a/b/video_normalized.mp4"},
+		{"/a/b/clip.MOV", "/a/b/clip_normalized.mp4"},
+		{"video", "video_normalized.mp4"},
+	}
+	for _, c := range cases {
+		if got := normalizedOutputPath(c.in); got != c.want {
+			t.Errorf("normalizedOutputPath(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestWipJobsRegisterCleanupRemovesOutputFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "partial_normalized.mp4")
+	if err := os.WriteFile(outputPath, []byte("partial"), 0644); err != nil {
+		t.Fatalf("writing partial output file: %v", err)
+	}
+
+	jobID := "test-job-register-cleanup"
+	registerWipJob(jobID)
+	defer unregisterWipJob(jobID)
+
+	addWipOutput(jobID, outputPath, nil)
+	cleanupWipJobs()
+
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Error("expected cleanupWipJobs to remove the tracked output file")
+	}
+}
+
+func TestAddWipOutputIsNoOpForUnregisteredJob(t *testing.T) {
+	// addWipOutput/removeWipOutput must not panic when called for a job ID
+	// that was never registered (e.g. after it already completed and was
+	// unregistered).
+	addWipOutput("never-registered", "/tmp/does-not-matter.mp4", nil)
+	removeWipOutput("never-registered", "/tmp/does-not-matter.mp4")
+}
+
+// TestCleanupWipJobsKillsTrackedProcess verifies that cleanup doesn't just
+// delete the tracked output file, it also kills the ffmpeg process that's
+// still writing to it, so an interrupted NormalizeVideos job doesn't leave
+// an orphaned ffmpeg running in the background.
+func TestCleanupWipJobsKillsTrackedProcess(t *testing.T) {
+	cmd := exec.Command("sleep", "5")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("couldn't start a long-running process to track: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "out_normalized.mp4")
+	if err := os.WriteFile(outputPath, []byte("partial"), 0644); err != nil {
+		t.Fatalf("writing partial output file: %v", err)
+	}
+
+	jobID := "test-job-kill-process"
+	registerWipJob(jobID)
+	defer unregisterWipJob(jobID)
+	addWipOutput(jobID, outputPath, cmd)
+
+	cleanupWipJobs()
+
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- cmd.Wait() }()
+
+	select {
+	case <-waitErr:
+		// The process exited, which is what a successful kill looks like.
+	case <-time.After(2 * time.Second):
+		cmd.Process.Kill()
+		t.Error("expected cleanupWipJobs to kill the tracked process, but it was still running")
+	}
+
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Error("expected cleanupWipJobs to remove the tracked output file")
+	}
+}